@@ -0,0 +1,21 @@
+package auth
+
+import (
+  "os/exec"
+  "runtime"
+)
+
+// openBrowser attempts to launch the system's default browser at
+// url. It is best-effort: callers should print url regardless, since
+// there is no reliable way to detect failure (e.g. no display, or a
+// headless SSH session) ahead of time.
+func openBrowser(url string) error {
+  switch runtime.GOOS {
+  case "darwin":
+    return exec.Command("open", url).Start()
+  case "windows":
+    return exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Start()
+  default:
+    return exec.Command("xdg-open", url).Start()
+  }
+}
@@ -0,0 +1,137 @@
+package auth
+
+import (
+  "context"
+  "fmt"
+  "log"
+  "net"
+  "net/http"
+  "os"
+  "runtime"
+
+  "golang.org/x/oauth2"
+)
+
+// callbackPage is shown in the browser once the loopback handler
+// receives the redirect; it intentionally holds no secrets.
+const callbackPage = `<!DOCTYPE html>
+<html><head><title>Authentication complete</title></head>
+<body><h1>Authentication complete</h1>
+<p>You may close this tab and return to the terminal.</p></body></html>`
+
+// GetToken obtains a token for config, either via a local loopback
+// HTTP server (the default) or, if noBrowser is true or no display
+// is available, by printing the auth URL and reading a pasted code
+// from stdin.
+func GetToken(ctx context.Context, config *oauth2.Config, noBrowser bool) (*oauth2.Token, error) {
+  if noBrowser || !hasDisplay() {
+    return getTokenFromStdin(ctx, config)
+  }
+  return getTokenFromLoopback(ctx, config)
+}
+
+// hasDisplay reports whether launching a browser is likely to work:
+// on Windows/macOS it always is, on other platforms it depends on a
+// graphical session being available.
+func hasDisplay() bool {
+  switch runtime.GOOS {
+  case "windows", "darwin":
+    return true
+  default:
+    return os.Getenv("DISPLAY") != "" || os.Getenv("WAYLAND_DISPLAY") != ""
+  }
+}
+
+// getTokenFromLoopback binds a one-shot HTTP server to a free port
+// on 127.0.0.1, points config's redirect URL at it, and exchanges the
+// authorization code for a token using PKCE once the browser
+// redirects back.
+func getTokenFromLoopback(ctx context.Context, config *oauth2.Config) (*oauth2.Token, error) {
+  ln, err := net.Listen("tcp", "127.0.0.1:0")
+  if err != nil {
+    return nil, fmt.Errorf("auth: binding loopback listener: %w", err)
+  }
+
+  port := ln.Addr().(*net.TCPAddr).Port
+  cfg := *config
+  cfg.RedirectURL = fmt.Sprintf("http://127.0.0.1:%d/callback", port)
+
+  state, err := newState()
+  if err != nil {
+    return nil, err
+  }
+  verifier, challenge, err := newPKCEPair()
+  if err != nil {
+    return nil, err
+  }
+
+  authURL := cfg.AuthCodeURL(state,
+    oauth2.AccessTypeOffline,
+    oauth2.SetAuthURLParam("code_challenge", challenge),
+    oauth2.SetAuthURLParam("code_challenge_method", "S256"))
+
+  type result struct {
+    tok *oauth2.Token
+    err error
+  }
+  resultCh := make(chan result, 1)
+
+  mux := http.NewServeMux()
+  mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+    q := r.URL.Query()
+    if errParam := q.Get("error"); errParam != "" {
+      resultCh <- result{err: fmt.Errorf("auth: authorization denied: %s", errParam)}
+      http.Error(w, "authorization denied", http.StatusBadRequest)
+      return
+    }
+    if q.Get("state") != state {
+      resultCh <- result{err: fmt.Errorf("auth: state mismatch in callback")}
+      http.Error(w, "state mismatch", http.StatusBadRequest)
+      return
+    }
+    tok, err := cfg.Exchange(r.Context(), q.Get("code"),
+      oauth2.SetAuthURLParam("code_verifier", verifier))
+    if err != nil {
+      resultCh <- result{err: fmt.Errorf("auth: exchanging code: %w", err)}
+      http.Error(w, "token exchange failed", http.StatusInternalServerError)
+      return
+    }
+    fmt.Fprint(w, callbackPage)
+    resultCh <- result{tok: tok}
+  })
+
+  srv := &http.Server{Handler: mux}
+  go func() {
+    if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+      log.Printf("auth: loopback server: %v", err)
+    }
+  }()
+  defer srv.Close()
+
+  fmt.Printf("Opening browser for authorization. If it doesn't open, visit:\n%v\n", authURL)
+  if err := openBrowser(authURL); err != nil {
+    log.Printf("auth: could not launch browser automatically: %v", err)
+  }
+
+  select {
+  case res := <-resultCh:
+    return res.tok, res.err
+  case <-ctx.Done():
+    return nil, ctx.Err()
+  }
+}
+
+// getTokenFromStdin is the pre-loopback flow: it prints the auth URL
+// and blocks on the user pasting back the authorization code. It is
+// used when --no-browser is passed or no display is detected.
+func getTokenFromStdin(ctx context.Context, config *oauth2.Config) (*oauth2.Token, error) {
+  authURL := config.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
+  fmt.Printf("Go to the following link in your browser then type the "+
+    "authorization code: \n%v\n", authURL)
+
+  var code string
+  if _, err := fmt.Scan(&code); err != nil {
+    return nil, fmt.Errorf("auth: reading authorization code: %w", err)
+  }
+  return config.Exchange(ctx, code)
+}
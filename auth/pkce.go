@@ -0,0 +1,38 @@
+// Package auth implements the interactive OAuth flows used to get an
+// initial token for the Drive API: a loopback-server flow with PKCE,
+// and a manual stdin fallback for environments with no browser.
+package auth
+
+import (
+  "crypto/rand"
+  "crypto/sha256"
+  "encoding/base64"
+)
+
+// newPKCEPair returns a random code_verifier and its S256
+// code_challenge, as defined by RFC 7636.
+func newPKCEPair() (verifier, challenge string, err error) {
+  verifier, err = randomURLSafeString(64)
+  if err != nil {
+    return "", "", err
+  }
+  sum := sha256.Sum256([]byte(verifier))
+  challenge = base64.RawURLEncoding.EncodeToString(sum[:])
+  return verifier, challenge, nil
+}
+
+// newState returns a random value suitable for the OAuth2 "state"
+// parameter, used to guard the callback against CSRF.
+func newState() (string, error) {
+  return randomURLSafeString(24)
+}
+
+// randomURLSafeString returns n bytes of crypto/rand, base64url
+// encoded without padding.
+func randomURLSafeString(n int) (string, error) {
+  b := make([]byte, n)
+  if _, err := rand.Read(b); err != nil {
+    return "", err
+  }
+  return base64.RawURLEncoding.EncodeToString(b), nil
+}
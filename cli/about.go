@@ -0,0 +1,35 @@
+package cli
+
+import (
+  "fmt"
+
+  "github.com/spf13/cobra"
+)
+
+func newAboutCmd() *cobra.Command {
+  return &cobra.Command{
+    Use:   "about",
+    Short: "Show the authenticated user and storage quota",
+    Args:  cobra.NoArgs,
+    RunE: func(cmd *cobra.Command, args []string) error {
+      svc, _, err := newService(cmd.Context())
+      if err != nil {
+        return err
+      }
+
+      abt, err := svc.About.Get().Fields("user, storageQuota, importFormats, exportFormats").Do()
+      if err != nil {
+        return fmt.Errorf("cli: retrieving about: %w", err)
+      }
+
+      out := cmd.OutOrStdout()
+      if abt.User != nil {
+        fmt.Fprintf(out, "User: %s <%s>\n", abt.User.DisplayName, abt.User.EmailAddress)
+      }
+      if abt.StorageQuota != nil {
+        fmt.Fprintf(out, "Quota: %d / %d bytes used\n", abt.StorageQuota.Usage, abt.StorageQuota.Limit)
+      }
+      return nil
+    },
+  }
+}
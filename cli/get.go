@@ -0,0 +1,47 @@
+package cli
+
+import (
+  "fmt"
+  "os"
+
+  "github.com/spf13/cobra"
+
+  "private/drivefs"
+  "private/transfer"
+)
+
+func newGetCmd() *cobra.Command {
+  return &cobra.Command{
+    Use:   "get <drive-path> <local-path>",
+    Short: "Download a file from Drive",
+    Args:  cobra.ExactArgs(2),
+    RunE: func(cmd *cobra.Command, args []string) error {
+      drivePath, localPath := args[0], args[1]
+      ctx := cmd.Context()
+
+      svc, _, err := newService(ctx)
+      if err != nil {
+        return err
+      }
+      driveFS := drivefs.New(svc, driveOpts()...)
+
+      id, err := driveFS.ID(drivePath)
+      if err != nil {
+        return fmt.Errorf("cli: resolving %s: %w", drivePath, err)
+      }
+
+      f, err := os.OpenFile(localPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+      if err != nil {
+        return err
+      }
+      defer f.Close()
+
+      n, err := transfer.Download(ctx, svc, id, f, -1, -1, nil)
+      if err != nil {
+        return err
+      }
+      fmt.Fprintf(cmd.OutOrStdout(), "wrote %d bytes to %s\n", n, localPath)
+      return nil
+    },
+  }
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+  "fmt"
+
+  "github.com/spf13/cobra"
+
+  "private/drivefs"
+)
+
+func newLsCmd() *cobra.Command {
+  return &cobra.Command{
+    Use:   "ls [path]",
+    Short: "List the contents of a Drive folder",
+    Args:  cobra.MaximumNArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+      p := "."
+      if len(args) == 1 {
+        p = args[0]
+      }
+
+      svc, _, err := newService(cmd.Context())
+      if err != nil {
+        return err
+      }
+      driveFS := drivefs.New(svc, driveOpts()...)
+
+      entries, err := driveFS.ReadDir(p)
+      if err != nil {
+        return err
+      }
+      for _, e := range entries {
+        kind := "-"
+        if e.IsDir() {
+          kind = "d"
+        }
+        fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", kind, e.Name())
+      }
+      return nil
+    },
+  }
+}
+
+// driveOpts returns the drivefs.Option set implied by the persistent
+// CLI flags (currently just --drive-id).
+func driveOpts() []drivefs.Option {
+  var opts []drivefs.Option
+  if driveIDFlag != "" {
+    opts = append(opts, drivefs.WithDriveID(driveIDFlag))
+  }
+  return opts
+}
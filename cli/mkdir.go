@@ -0,0 +1,46 @@
+package cli
+
+import (
+  "fmt"
+
+  "github.com/spf13/cobra"
+  "google.golang.org/api/drive/v3"
+
+  "private/drivefs"
+)
+
+const driveFolderMIMEType = "application/vnd.google-apps.folder"
+
+func newMkdirCmd() *cobra.Command {
+  return &cobra.Command{
+    Use:   "mkdir <drive-path>",
+    Short: "Create a folder",
+    Args:  cobra.ExactArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+      drivePath := args[0]
+      ctx := cmd.Context()
+
+      svc, _, err := newService(ctx)
+      if err != nil {
+        return err
+      }
+      driveFS := drivefs.New(svc, driveOpts()...)
+
+      parentID, name, err := driveFS.ResolveParent(drivePath)
+      if err != nil {
+        return fmt.Errorf("cli: resolving %s: %w", drivePath, err)
+      }
+
+      f, err := svc.Files.Create(&drive.File{
+        Name:     name,
+        MimeType: driveFolderMIMEType,
+        Parents:  []string{parentID},
+      }).Do()
+      if err != nil {
+        return fmt.Errorf("cli: creating folder %s: %w", drivePath, err)
+      }
+      fmt.Fprintf(cmd.OutOrStdout(), "created %s (%s)\n", drivePath, f.Id)
+      return nil
+    },
+  }
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+  "fmt"
+
+  "github.com/spf13/cobra"
+  "google.golang.org/api/drive/v3"
+
+  "private/drivefs"
+)
+
+func newMvCmd() *cobra.Command {
+  return &cobra.Command{
+    Use:   "mv <src> <dst>",
+    Short: "Move or rename a file or folder",
+    Args:  cobra.ExactArgs(2),
+    RunE: func(cmd *cobra.Command, args []string) error {
+      src, dst := args[0], args[1]
+      ctx := cmd.Context()
+
+      svc, _, err := newService(ctx)
+      if err != nil {
+        return err
+      }
+      driveFS := drivefs.New(svc, driveOpts()...)
+
+      srcID, err := driveFS.ID(src)
+      if err != nil {
+        return fmt.Errorf("cli: resolving %s: %w", src, err)
+      }
+      srcParentID, _, err := driveFS.ResolveParent(src)
+      if err != nil {
+        return fmt.Errorf("cli: resolving %s's parent: %w", src, err)
+      }
+      dstParentID, dstName, err := driveFS.ResolveParent(dst)
+      if err != nil {
+        return fmt.Errorf("cli: resolving %s: %w", dst, err)
+      }
+
+      call := svc.Files.Update(srcID, &drive.File{Name: dstName}).
+        AddParents(dstParentID).
+        RemoveParents(srcParentID)
+      if _, err := call.Do(); err != nil {
+        return fmt.Errorf("cli: moving %s to %s: %w", src, dst, err)
+      }
+      fmt.Fprintf(cmd.OutOrStdout(), "moved %s to %s\n", src, dst)
+      return nil
+    },
+  }
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+  "fmt"
+  "io/fs"
+  "mime"
+  "os"
+  "path/filepath"
+
+  "github.com/spf13/cobra"
+
+  "private/drivefs"
+  "private/transfer"
+)
+
+func newPutCmd() *cobra.Command {
+  return &cobra.Command{
+    Use:   "put <local-path> <drive-path>",
+    Short: "Upload a local file to Drive, creating or overwriting it",
+    Args:  cobra.ExactArgs(2),
+    RunE: func(cmd *cobra.Command, args []string) error {
+      localPath, drivePath := args[0], args[1]
+      ctx := cmd.Context()
+
+      svc, client, err := newService(ctx)
+      if err != nil {
+        return err
+      }
+      driveFS := drivefs.New(svc, driveOpts()...)
+
+      f, err := os.Open(localPath)
+      if err != nil {
+        return err
+      }
+      defer f.Close()
+      info, err := f.Stat()
+      if err != nil {
+        return err
+      }
+
+      mimeType := mime.TypeByExtension(filepath.Ext(localPath))
+      if mimeType == "" {
+        mimeType = "application/octet-stream"
+      }
+
+      fileID, parentID, name, err := resolveForPut(driveFS, drivePath)
+      if err != nil {
+        return err
+      }
+
+      sessions, err := newSessionStore()
+      if err != nil {
+        return err
+      }
+      uploader := &transfer.Uploader{Client: client, Sessions: sessions}
+      key := uploadSessionKey(drivePath, info.Size(), info.ModTime())
+      result, err := uploader.Upload(ctx, fileID, parentID, name, mimeType, f, info.Size(), key)
+      if err != nil {
+        return err
+      }
+      fmt.Fprintf(cmd.OutOrStdout(), "uploaded %s as %s (%s)\n", localPath, result.Name, result.Id)
+      return nil
+    },
+  }
+}
+
+// resolveForPut returns the file ID to update (with no parent/name
+// change) if drivePath already exists, or an empty file ID plus the
+// parent folder ID and name to create it under if it doesn't.
+func resolveForPut(driveFS *drivefs.FS, drivePath string) (fileID, parentID, name string, err error) {
+  id, err := driveFS.ID(drivePath)
+  if err == nil {
+    return id, "", "", nil
+  }
+  if !isNotExist(err) {
+    return "", "", "", err
+  }
+  parent, base, err := driveFS.ResolveParent(drivePath)
+  if err != nil {
+    return "", "", "", err
+  }
+  return "", parent, base, nil
+}
+
+func isNotExist(err error) bool {
+  pe, ok := err.(*fs.PathError)
+  return ok && pe.Err == fs.ErrNotExist
+}
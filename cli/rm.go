@@ -0,0 +1,39 @@
+package cli
+
+import (
+  "fmt"
+
+  "github.com/spf13/cobra"
+  "google.golang.org/api/drive/v3"
+
+  "private/drivefs"
+)
+
+func newRmCmd() *cobra.Command {
+  return &cobra.Command{
+    Use:   "rm <drive-path>",
+    Short: "Trash a file or folder",
+    Args:  cobra.ExactArgs(1),
+    RunE: func(cmd *cobra.Command, args []string) error {
+      drivePath := args[0]
+      ctx := cmd.Context()
+
+      svc, _, err := newService(ctx)
+      if err != nil {
+        return err
+      }
+      driveFS := drivefs.New(svc, driveOpts()...)
+
+      id, err := driveFS.ID(drivePath)
+      if err != nil {
+        return fmt.Errorf("cli: resolving %s: %w", drivePath, err)
+      }
+
+      if _, err := svc.Files.Update(id, &drive.File{Trashed: true}).Do(); err != nil {
+        return fmt.Errorf("cli: trashing %s: %w", drivePath, err)
+      }
+      fmt.Fprintf(cmd.OutOrStdout(), "trashed %s\n", drivePath)
+      return nil
+    },
+  }
+}
@@ -0,0 +1,166 @@
+// Package cli implements the drive command-line tool: a set of
+// subcommands (ls, get, put, rm, mv, mkdir, sync, export, about)
+// layered on top of the drivefs, transfer, tokenstore, and auth
+// packages.
+package cli
+
+import (
+  "context"
+  "fmt"
+  "io/ioutil"
+  "net/http"
+  "net/url"
+  "os"
+  "os/user"
+  "path/filepath"
+  "time"
+
+  "golang.org/x/oauth2/google"
+  "google.golang.org/api/drive/v3"
+
+  "github.com/spf13/cobra"
+
+  "private/auth"
+  "private/tokenstore"
+  "private/transfer"
+)
+
+var (
+  authModeFlag    string
+  tokenStoreFlag  string
+  credentialsFlag string
+  noBrowserFlag   bool
+  driveIDFlag     string
+)
+
+// NewRootCmd builds the drive CLI's root command. main just calls
+// Execute on the result.
+func NewRootCmd() *cobra.Command {
+  root := &cobra.Command{
+    Use:           "drive",
+    Short:         "Command-line client for Google Drive",
+    SilenceUsage:  true,
+    SilenceErrors: true,
+  }
+
+  root.PersistentFlags().StringVar(&authModeFlag, "auth-mode", string(auth.ModeUser),
+    "credential source: user, service-account, or adc")
+  root.PersistentFlags().StringVar(&tokenStoreFlag, "token-store", "file",
+    "where the user token is cached: file, keyring, or memory")
+  root.PersistentFlags().StringVar(&credentialsFlag, "credentials", "private/client_secret.json",
+    "OAuth client secret (user mode) or service account key (service-account mode)")
+  root.PersistentFlags().BoolVar(&noBrowserFlag, "no-browser", false,
+    "use the manual copy-paste auth flow instead of the loopback browser flow")
+  root.PersistentFlags().StringVar(&driveIDFlag, "drive-id", "",
+    "operate against the given shared drive instead of My Drive")
+
+  root.AddCommand(
+    newLsCmd(),
+    newGetCmd(),
+    newPutCmd(),
+    newRmCmd(),
+    newMvCmd(),
+    newMkdirCmd(),
+    newSyncCmd(),
+    newExportCmd(),
+    newAboutCmd(),
+  )
+  return root
+}
+
+// newService builds an authenticated drive.Service per the
+// persistent --auth-mode/--token-store/--credentials flags.
+func newService(ctx context.Context) (*drive.Service, *http.Client, error) {
+  client, err := newClient(ctx)
+  if err != nil {
+    return nil, nil, err
+  }
+  svc, err := drive.New(client)
+  if err != nil {
+    return nil, nil, fmt.Errorf("cli: building drive client: %w", err)
+  }
+  return svc, client, nil
+}
+
+func newClient(ctx context.Context) (*http.Client, error) {
+  switch auth.Mode(authModeFlag) {
+  case auth.ModeServiceAccount:
+    return auth.ServiceAccountClient(ctx, credentialsFlag, drive.DriveScope)
+  case auth.ModeADC:
+    return auth.DefaultClient(ctx, drive.DriveScope)
+  default:
+    return newUserClient(ctx)
+  }
+}
+
+func newUserClient(ctx context.Context) (*http.Client, error) {
+  b, err := ioutil.ReadFile(credentialsFlag)
+  if err != nil {
+    return nil, fmt.Errorf("cli: reading client secret file: %w", err)
+  }
+  config, err := google.ConfigFromJSON(b, drive.DriveScope)
+  if err != nil {
+    return nil, fmt.Errorf("cli: parsing client secret file: %w", err)
+  }
+
+  store, err := newTokenStore()
+  if err != nil {
+    return nil, err
+  }
+  tok, err := store.Load(ctx)
+  if err != nil {
+    tok, err = auth.GetToken(ctx, config, noBrowserFlag)
+    if err != nil {
+      return nil, fmt.Errorf("cli: retrieving token: %w", err)
+    }
+    if err := store.Save(ctx, tok); err != nil {
+      fmt.Fprintf(os.Stderr, "warning: unable to cache oauth token: %v\n", err)
+    }
+  }
+  return config.Client(ctx, tok), nil
+}
+
+// newSessionStore returns the SessionStore an Uploader uses to resume
+// an interrupted upload across process restarts, persisted next to
+// the cached OAuth token. Sessions are scoped under the active
+// --drive-id (or "mydrive" without one), so an interrupted upload
+// can never be resumed against the wrong drive just because two
+// drives happen to share a path.
+func newSessionStore() (transfer.SessionStore, error) {
+  usr, err := user.Current()
+  if err != nil {
+    return nil, fmt.Errorf("cli: determining home directory for the upload session cache: %w", err)
+  }
+  scope := "mydrive"
+  if driveIDFlag != "" {
+    scope = url.QueryEscape(driveIDFlag)
+  }
+  dir := filepath.Join(usr.HomeDir, ".credentials", "upload-sessions", scope)
+  return transfer.FileSessionStore{Dir: dir}, nil
+}
+
+// uploadSessionKey identifies a resumable upload session for
+// drivePath. It folds in the local file's size and modification
+// time so that editing the file (or overwriting it with unrelated
+// content of a different size/mtime between runs) starts a fresh
+// session instead of resuming one against content it was never
+// uploading.
+func uploadSessionKey(drivePath string, size int64, modTime time.Time) string {
+  return fmt.Sprintf("%s:%d:%d", drivePath, size, modTime.UnixNano())
+}
+
+func newTokenStore() (tokenstore.TokenStore, error) {
+  switch tokenStoreFlag {
+  case "keyring":
+    return tokenstore.Keyring{}, nil
+  case "memory":
+    return &tokenstore.Memory{}, nil
+  default:
+    usr, err := user.Current()
+    if err != nil {
+      return nil, fmt.Errorf("cli: determining home directory for the token cache: %w", err)
+    }
+    path := filepath.Join(usr.HomeDir, ".credentials", url.QueryEscape("drive-go-quickstart.json"))
+    return tokenstore.File{Path: path}, nil
+  }
+}
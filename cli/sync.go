@@ -0,0 +1,346 @@
+package cli
+
+import (
+  "context"
+  "crypto/md5"
+  "encoding/hex"
+  "fmt"
+  "io"
+  "io/fs"
+  "mime"
+  "os"
+  "path"
+  "path/filepath"
+  "sort"
+  "time"
+
+  "github.com/spf13/cobra"
+  "google.golang.org/api/drive/v3"
+
+  "private/drivefs"
+  "private/transfer"
+)
+
+type localFile struct {
+  modTime time.Time
+  md5     string
+  isDir   bool
+}
+
+type remoteFile struct {
+  id      string
+  modTime time.Time
+  md5     string
+  isDir   bool
+}
+
+func newSyncCmd() *cobra.Command {
+  var deleteFlag bool
+
+  cmd := &cobra.Command{
+    Use:   "sync <local-dir> <drive-path>",
+    Short: "Bidirectionally reconcile a local directory with a Drive folder",
+    Long: "Sync compares local files against a Drive folder using Drive's " +
+      "md5Checksum and the local file's content hash, against the hashes recorded " +
+      "the last time each file matched between the two sides (kept in " +
+      syncStateFile + " inside local-dir). Modification times are recorded in " +
+      "that index but do not decide what is pushed or pulled. A file changed on " +
+      "only one side since then is pushed or pulled; changed on both sides is " +
+      "reported as a conflict and left alone.",
+    Args: cobra.ExactArgs(2),
+    RunE: func(cmd *cobra.Command, args []string) error {
+      return runSync(cmd, args[0], args[1], deleteFlag)
+    },
+  }
+  cmd.Flags().BoolVar(&deleteFlag, "delete", false,
+    "propagate deletions detected on one side to the other")
+  return cmd
+}
+
+func runSync(cmd *cobra.Command, localDir, drivePath string, deleteRemote bool) error {
+  ctx := cmd.Context()
+  out := cmd.OutOrStdout()
+
+  svc, client, err := newService(ctx)
+  if err != nil {
+    return err
+  }
+  driveFS := drivefs.New(svc, driveOpts()...)
+
+  statePath := filepath.Join(localDir, syncStateFile)
+  state, err := loadSyncState(statePath)
+  if err != nil {
+    return fmt.Errorf("cli: reading sync state: %w", err)
+  }
+
+  local, err := walkLocal(localDir)
+  if err != nil {
+    return fmt.Errorf("cli: walking %s: %w", localDir, err)
+  }
+  remote, err := walkRemote(driveFS, drivePath)
+  if err != nil {
+    return fmt.Errorf("cli: walking %s: %w", drivePath, err)
+  }
+
+  rels := make(map[string]bool, len(local)+len(remote))
+  for rel := range local {
+    rels[rel] = true
+  }
+  for rel := range remote {
+    rels[rel] = true
+  }
+  sorted := make([]string, 0, len(rels))
+  for rel := range rels {
+    sorted = append(sorted, rel)
+  }
+  sort.Strings(sorted)
+
+  sessions, err := newSessionStore()
+  if err != nil {
+    return err
+  }
+  uploader := &transfer.Uploader{Client: client, Sessions: sessions}
+
+  for _, rel := range sorted {
+    l, hasLocal := local[rel]
+    r, hasRemote := remote[rel]
+    rec, hadRecord := state.Records[rel]
+
+    switch {
+    case hasLocal && l.isDir:
+      if !hasRemote {
+        if err := createRemoteDir(driveFS, svc, drivePath, rel); err != nil {
+          return err
+        }
+      }
+      continue
+    case hasRemote && r.isDir:
+      if !hasLocal {
+        if err := os.MkdirAll(filepath.Join(localDir, rel), 0755); err != nil {
+          return err
+        }
+      }
+      continue
+
+    case hasLocal && hasRemote:
+      localChanged := !hadRecord || l.md5 != rec.LocalMD5
+      remoteChanged := !hadRecord || r.md5 != rec.DriveMD5
+      switch {
+      case l.md5 == r.md5:
+        // Already in sync; just refresh the record below.
+      case localChanged && remoteChanged:
+        fmt.Fprintf(out, "conflict: %s changed on both sides, skipping\n", rel)
+        continue
+      case remoteChanged:
+        if err := pull(ctx, svc, localDir, rel, r); err != nil {
+          return err
+        }
+        fmt.Fprintf(out, "pulled %s\n", rel)
+      default:
+        if err := push(ctx, uploader, driveFS, localDir, drivePath, rel, r.id); err != nil {
+          return err
+        }
+        fmt.Fprintf(out, "pushed %s\n", rel)
+      }
+
+    case hasLocal && !hasRemote:
+      if hadRecord {
+        if !deleteRemote {
+          // Leave the record alone: it's what makes hadRecord true again
+          // next run, so the skip stays stable instead of the surviving
+          // local copy being mistaken for new and pushed back.
+          fmt.Fprintf(out, "skipping %s: deleted on Drive, local copy kept (pass --delete to remove it)\n", rel)
+          continue
+        }
+        if err := os.Remove(filepath.Join(localDir, rel)); err != nil {
+          return err
+        }
+        fmt.Fprintf(out, "removed local %s (deleted on Drive)\n", rel)
+        delete(state.Records, rel)
+        continue
+      }
+      if err := push(ctx, uploader, driveFS, localDir, drivePath, rel, ""); err != nil {
+        return err
+      }
+      fmt.Fprintf(out, "pushed %s\n", rel)
+
+    case hasRemote && !hasLocal:
+      if hadRecord {
+        if !deleteRemote {
+          // Same as the hasLocal branch above: keep the record so the
+          // skip is stable across repeated runs instead of re-pulling
+          // the surviving Drive copy as though it were newly created.
+          fmt.Fprintf(out, "skipping %s: deleted locally, Drive copy kept (pass --delete to remove it)\n", rel)
+          continue
+        }
+        if _, err := svc.Files.Update(r.id, &drive.File{Trashed: true}).Do(); err != nil {
+          return err
+        }
+        fmt.Fprintf(out, "trashed Drive copy of %s (deleted locally)\n", rel)
+        delete(state.Records, rel)
+        continue
+      }
+      if err := pull(ctx, svc, localDir, rel, r); err != nil {
+        return err
+      }
+      fmt.Fprintf(out, "pulled %s\n", rel)
+    }
+
+    // Re-stat both sides after any push/pull so the record reflects
+    // what's now on disk/Drive, not what triggered the sync.
+    nl, _ := statLocal(filepath.Join(localDir, rel))
+    modTime, md5sum, _, _ := driveFS.Meta(filepath.Join(drivePath, rel))
+    id, _ := driveFS.ID(filepath.Join(drivePath, rel))
+    state.Records[rel] = syncRecord{
+      LocalMD5: nl.md5, LocalModTime: nl.modTime,
+      DriveMD5: md5sum, DriveModTime: modTime,
+      FileID: id,
+    }
+  }
+
+  if err := state.save(statePath); err != nil {
+    return fmt.Errorf("cli: writing sync state: %w", err)
+  }
+  return nil
+}
+
+// walkLocal returns every regular file and directory under dir,
+// keyed by path relative to dir, skipping the sync state file
+// itself.
+func walkLocal(dir string) (map[string]localFile, error) {
+  files := make(map[string]localFile)
+  err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+    if err != nil {
+      return err
+    }
+    rel, err := filepath.Rel(dir, p)
+    if err != nil || rel == "." || rel == syncStateFile {
+      return nil
+    }
+    if info.IsDir() {
+      files[rel] = localFile{isDir: true, modTime: info.ModTime()}
+      return nil
+    }
+    sum, err := md5File(p)
+    if err != nil {
+      return err
+    }
+    files[rel] = localFile{modTime: info.ModTime(), md5: sum}
+    return nil
+  })
+  return files, err
+}
+
+func md5File(path string) (string, error) {
+  f, err := os.Open(path)
+  if err != nil {
+    return "", err
+  }
+  defer f.Close()
+  h := md5.New()
+  if _, err := io.Copy(h, f); err != nil {
+    return "", err
+  }
+  return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func statLocal(path string) (localFile, error) {
+  info, err := os.Stat(path)
+  if err != nil {
+    return localFile{}, err
+  }
+  sum, err := md5File(path)
+  if err != nil {
+    return localFile{}, err
+  }
+  return localFile{modTime: info.ModTime(), md5: sum}, nil
+}
+
+// walkRemote returns every file and folder under drivePath, keyed by
+// path relative to drivePath.
+func walkRemote(driveFS *drivefs.FS, drivePath string) (map[string]remoteFile, error) {
+  sub, err := driveFS.Sub(drivePath)
+  if err != nil {
+    if isNotExist(err) {
+      return map[string]remoteFile{}, nil
+    }
+    return nil, err
+  }
+
+  files := make(map[string]remoteFile)
+  err = fs.WalkDir(sub, ".", func(p string, d fs.DirEntry, err error) error {
+    if err != nil || p == "." {
+      return err
+    }
+    modTime, sum, _, merr := driveFS.Meta(filepath.Join(drivePath, p))
+    if merr != nil {
+      return merr
+    }
+    id, ierr := driveFS.ID(filepath.Join(drivePath, p))
+    if ierr != nil {
+      return ierr
+    }
+    files[p] = remoteFile{id: id, modTime: modTime, md5: sum, isDir: d.IsDir()}
+    return nil
+  })
+  return files, err
+}
+
+func push(ctx context.Context, uploader *transfer.Uploader, driveFS *drivefs.FS, localDir, drivePath, rel, fileID string) error {
+  f, err := os.Open(filepath.Join(localDir, rel))
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  info, err := f.Stat()
+  if err != nil {
+    return err
+  }
+
+  var parentID, name string
+  if fileID == "" {
+    parentID, name, err = driveFS.ResolveParent(filepath.Join(drivePath, rel))
+    if err != nil {
+      return err
+    }
+  }
+  mimeType := mime.TypeByExtension(filepath.Ext(rel))
+  key := uploadSessionKey(filepath.Join(drivePath, rel), info.Size(), info.ModTime())
+  if _, err := uploader.Upload(ctx, fileID, parentID, name, mimeType, f, info.Size(), key); err != nil {
+    return err
+  }
+  // The upload went through transfer.Uploader directly, bypassing
+  // driveFS, so its cached listing of the parent directory is now
+  // stale; drop it before the caller reads the new file back.
+  driveFS.Invalidate(path.Dir(filepath.Join(drivePath, rel)))
+  return nil
+}
+
+func pull(ctx context.Context, svc *drive.Service, localDir, rel string, r remoteFile) error {
+  if err := os.MkdirAll(filepath.Dir(filepath.Join(localDir, rel)), 0755); err != nil {
+    return err
+  }
+  f, err := os.OpenFile(filepath.Join(localDir, rel), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+  if err != nil {
+    return err
+  }
+  defer f.Close()
+  _, err = transfer.Download(ctx, svc, r.id, f, -1, -1, nil)
+  return err
+}
+
+func createRemoteDir(driveFS *drivefs.FS, svc *drive.Service, drivePath, rel string) error {
+  parentID, name, err := driveFS.ResolveParent(filepath.Join(drivePath, rel))
+  if err != nil {
+    return err
+  }
+  if _, err := svc.Files.Create(&drive.File{
+    Name:     name,
+    MimeType: driveFolderMIMEType,
+    Parents:  []string{parentID},
+  }).Do(); err != nil {
+    return err
+  }
+  driveFS.Invalidate(path.Dir(filepath.Join(drivePath, rel)))
+  return nil
+}
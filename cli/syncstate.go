@@ -0,0 +1,56 @@
+package cli
+
+import (
+  "encoding/json"
+  "io/ioutil"
+  "os"
+  "time"
+)
+
+// syncRecord is the last-known state of one file on each side, as of
+// the last successful sync. Comparing it against the file's current
+// state on each side is how sync tells "changed since we last looked"
+// apart from "differs because the other side just hasn't caught up".
+type syncRecord struct {
+  LocalMD5     string    `json:"localMd5"`
+  LocalModTime time.Time `json:"localModTime"`
+  DriveMD5     string    `json:"driveMd5"`
+  DriveModTime time.Time `json:"driveModTime"`
+  FileID       string    `json:"fileId"`
+}
+
+// syncState is the on-disk index sync uses to detect changes and
+// conflicts, keyed by path relative to the synced directory.
+type syncState struct {
+  Records map[string]syncRecord `json:"records"`
+}
+
+// syncStateFile is the name of the index file sync keeps inside the
+// local directory it's syncing.
+const syncStateFile = ".drivesync.json"
+
+func loadSyncState(path string) (*syncState, error) {
+  b, err := ioutil.ReadFile(path)
+  if os.IsNotExist(err) {
+    return &syncState{Records: map[string]syncRecord{}}, nil
+  }
+  if err != nil {
+    return nil, err
+  }
+  var s syncState
+  if err := json.Unmarshal(b, &s); err != nil {
+    return nil, err
+  }
+  if s.Records == nil {
+    s.Records = map[string]syncRecord{}
+  }
+  return &s, nil
+}
+
+func (s *syncState) save(path string) error {
+  b, err := json.MarshalIndent(s, "", "  ")
+  if err != nil {
+    return err
+  }
+  return ioutil.WriteFile(path, b, 0644)
+}
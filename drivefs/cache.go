@@ -0,0 +1,76 @@
+package drivefs
+
+import (
+  "sync"
+  "time"
+)
+
+// dirEntry is a single child of a resolved directory: the name it is
+// addressed by within the FS (after duplicate-name suffixing) and
+// the underlying Drive file ID it resolves to.
+type dirEntry struct {
+  name     string
+  id       string
+  isDir    bool
+  mimeType string
+  modTime  time.Time
+  md5      string
+  size     int64
+}
+
+// dirListing is the cached result of resolving one directory's
+// children.
+type dirListing struct {
+  entries []dirEntry
+  expires time.Time
+}
+
+// dirCache caches directory ID -> resolved children for ttl, so that
+// repeated Open/Stat/ReadDir calls against the same directory (as
+// fs.WalkDir makes while descending a tree) don't each re-issue the
+// Files.List round trip.
+type dirCache struct {
+  ttl time.Duration
+
+  mu      sync.Mutex
+  entries map[string]dirListing
+}
+
+func newDirCache(ttl time.Duration) *dirCache {
+  return &dirCache{ttl: ttl, entries: make(map[string]dirListing)}
+}
+
+// get returns the cached children of the directory with the given
+// file ID, if present and not expired.
+func (c *dirCache) get(dirID string) ([]dirEntry, bool) {
+  if c.ttl <= 0 {
+    return nil, false
+  }
+  c.mu.Lock()
+  defer c.mu.Unlock()
+  l, ok := c.entries[dirID]
+  if !ok || time.Now().After(l.expires) {
+    return nil, false
+  }
+  return l.entries, true
+}
+
+// put stores the resolved children of the directory with the given
+// file ID.
+func (c *dirCache) put(dirID string, entries []dirEntry) {
+  if c.ttl <= 0 {
+    return
+  }
+  c.mu.Lock()
+  defer c.mu.Unlock()
+  c.entries[dirID] = dirListing{entries: entries, expires: time.Now().Add(c.ttl)}
+}
+
+// invalidate drops any cached listing for the given directory. FS's
+// Invalidate calls it after a caller mutates Drive outside this
+// package, so that the next lookup re-resolves from Drive.
+func (c *dirCache) invalidate(dirID string) {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+  delete(c.entries, dirID)
+}
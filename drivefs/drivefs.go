@@ -0,0 +1,123 @@
+// Package drivefs exposes a Google Drive hierarchy as a standard
+// io/fs.FS, so callers can use fs.WalkDir, fs.Glob, and friends
+// against a Drive account instead of calling the Files API directly.
+//
+// Drive is not a filesystem: it is a DAG keyed by opaque file IDs,
+// a folder may contain two children with the same name, and a name
+// can appear under more than one parent (multi-parenting). FS
+// resolves POSIX-style paths onto that graph by walking one segment
+// at a time from the root (or DriveID, for a shared drive) and
+// caching the name->id lookups it makes along the way.
+package drivefs
+
+import (
+  "io/fs"
+  "time"
+
+  "google.golang.org/api/drive/v3"
+)
+
+// exportMIME maps a Google-native MIME type to the MIME type it
+// should be exported as when opened through the FS.
+type exportMIME map[string]string
+
+// DefaultExportMIME exports Docs, Sheets, and Slides to PDF, which is
+// the one format every Google editor type supports.
+var DefaultExportMIME = exportMIME{
+  "application/vnd.google-apps.document":     "application/pdf",
+  "application/vnd.google-apps.spreadsheet":  "application/pdf",
+  "application/vnd.google-apps.presentation": "application/pdf",
+}
+
+// FS implements io/fs.FS, fs.ReadDirFS, fs.StatFS, and fs.SubFS on
+// top of a drive.Service. The zero value is not usable; construct
+// one with New.
+type FS struct {
+  svc *drive.Service
+
+  // driveID, if set, roots the FS at a shared drive instead of the
+  // caller's My Drive, and tells children to query that drive's
+  // corpora. Only WithDriveID sets this.
+  driveID string
+
+  // root, if set, overrides the file ID path resolution starts from,
+  // without implying a shared drive. Sub sets this so a sub-FS
+  // rooted at an ordinary folder (or even the real root) doesn't
+  // make children think it's a shared drive.
+  root string
+
+  // export controls how Google-native files (Docs, Sheets, Slides)
+  // are surfaced. If nil, native files are hidden from directory
+  // listings and Open/Stat return fs.ErrNotExist for them.
+  export exportMIME
+
+  cache *dirCache
+}
+
+// Option configures an FS returned by New.
+type Option func(*FS)
+
+// WithDriveID roots the FS at the given shared drive instead of the
+// authenticated user's My Drive.
+func WithDriveID(id string) Option {
+  return func(f *FS) { f.driveID = id }
+}
+
+// WithExportMIME sets the MIME types that Google-native files (Docs,
+// Sheets, Slides) are exported as when read. Pass nil (the default)
+// to hide native files from the FS entirely.
+func WithExportMIME(m map[string]string) Option {
+  return func(f *FS) {
+    if m == nil {
+      f.export = nil
+      return
+    }
+    em := make(exportMIME, len(m))
+    for k, v := range m {
+      em[k] = v
+    }
+    f.export = em
+  }
+}
+
+// WithCacheTTL overrides the default TTL used to cache resolved
+// directory listings. A TTL of zero disables caching.
+func WithCacheTTL(ttl time.Duration) Option {
+  return func(f *FS) { f.cache.ttl = ttl }
+}
+
+// defaultCacheTTL amortizes the N+1 round trips fs.WalkDir makes
+// against a directory it has already listed once.
+const defaultCacheTTL = 30 * time.Second
+
+// New returns an FS backed by svc. The root of the FS is the
+// authenticated user's My Drive root, or the shared drive passed via
+// WithDriveID.
+func New(svc *drive.Service, opts ...Option) *FS {
+  f := &FS{
+    svc:   svc,
+    cache: newDirCache(defaultCacheTTL),
+  }
+  for _, opt := range opts {
+    opt(f)
+  }
+  return f
+}
+
+// rootID returns the file ID that path resolution starts from.
+func (f *FS) rootID() string {
+  if f.root != "" {
+    return f.root
+  }
+  if f.driveID != "" {
+    return f.driveID
+  }
+  return "root"
+}
+
+var (
+  _ fs.FS         = (*FS)(nil)
+  _ fs.ReadDirFS  = (*FS)(nil)
+  _ fs.StatFS     = (*FS)(nil)
+  _ fs.SubFS      = (*FS)(nil)
+)
@@ -0,0 +1,221 @@
+package drivefs
+
+import (
+  "context"
+  "io"
+  "io/fs"
+  "time"
+)
+
+// Open implements fs.FS. It resolves name against the FS root and
+// returns a handle that streams the file's content (downloading it,
+// or exporting it to the configured MIME if it is Google-native).
+func (f *FS) Open(name string) (fs.File, error) {
+  ctx := context.Background()
+  ent, err := f.resolve(ctx, name)
+  if err != nil {
+    return nil, err
+  }
+  if ent.isDir {
+    kids, err := f.children(ctx, ent.id)
+    if err != nil {
+      return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+    }
+    return &openDir{name: name, entries: kids}, nil
+  }
+  return &openFile{fsys: f, name: name, ent: ent}, nil
+}
+
+// ReadDir implements fs.ReadDirFS.
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+  ent, err := f.resolve(context.Background(), name)
+  if err != nil {
+    return nil, err
+  }
+  if !ent.isDir {
+    return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+  }
+  kids, err := f.children(context.Background(), ent.id)
+  if err != nil {
+    return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+  }
+  out := make([]fs.DirEntry, len(kids))
+  for i, k := range kids {
+    out[i] = dirEntryInfo{k}
+  }
+  return out, nil
+}
+
+// Stat implements fs.StatFS.
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+  ent, err := f.resolve(context.Background(), name)
+  if err != nil {
+    return nil, err
+  }
+  return dirEntryInfo{ent}, nil
+}
+
+// ID resolves name to the underlying Drive file ID it addresses.
+// Callers that need to issue raw Files.* API calls (rather than
+// going through the fs.FS surface) use this to bridge a path onto an
+// ID once.
+func (f *FS) ID(name string) (string, error) {
+  ent, err := f.resolve(context.Background(), name)
+  if err != nil {
+    return "", err
+  }
+  return ent.id, nil
+}
+
+// Invalidate drops the cached listing of dir, so the next lookup
+// under it re-resolves from Drive instead of returning a listing
+// taken before a caller-initiated write (e.g. through transfer.Uploader
+// or Files.Create called directly, bypassing this FS). Callers that
+// mutate Drive outside of this package call it on the affected
+// directory when they need their next read through the FS to see
+// the change.
+func (f *FS) Invalidate(dir string) error {
+  ent, err := f.resolve(context.Background(), dir)
+  if err != nil {
+    return err
+  }
+  f.cache.invalidate(ent.id)
+  return nil
+}
+
+// Meta resolves name and returns the Drive metadata sync needs to
+// decide whether it differs from a previous snapshot: modification
+// time, MD5 checksum, and size. Content-addressed comparisons
+// (md5Checksum) are preferred over modifiedTime alone, since Drive
+// bumps modifiedTime on metadata-only changes too.
+func (f *FS) Meta(name string) (modTime time.Time, md5 string, size int64, err error) {
+  ent, err := f.resolve(context.Background(), name)
+  if err != nil {
+    return time.Time{}, "", 0, err
+  }
+  return ent.modTime, ent.md5, ent.size, nil
+}
+
+// Sub implements fs.SubFS. The returned FS shares this FS's cache
+// and configuration but is rooted at dir. This overrides root, not
+// driveID, so a sub-FS of an ordinary folder (the common case) isn't
+// mistaken by children for a shared drive and doesn't pick up the
+// driveId/corpora query parameters that only apply to one.
+func (f *FS) Sub(dir string) (fs.FS, error) {
+  ent, err := f.resolve(context.Background(), dir)
+  if err != nil {
+    return nil, err
+  }
+  if !ent.isDir {
+    return nil, &fs.PathError{Op: "sub", Path: dir, Err: fs.ErrInvalid}
+  }
+  sub := *f
+  sub.root = ent.id
+  return &sub, nil
+}
+
+// dirEntryInfo adapts a dirEntry to both fs.DirEntry and fs.FileInfo.
+type dirEntryInfo struct{ e dirEntry }
+
+func (d dirEntryInfo) Name() string      { return d.e.name }
+func (d dirEntryInfo) IsDir() bool       { return d.e.isDir }
+func (d dirEntryInfo) Type() fs.FileMode { return d.Mode().Type() }
+func (d dirEntryInfo) Info() (fs.FileInfo, error) { return d, nil }
+func (d dirEntryInfo) Size() int64       { return d.e.size }
+func (d dirEntryInfo) ModTime() time.Time { return d.e.modTime }
+func (d dirEntryInfo) Sys() interface{}  { return d.e }
+func (d dirEntryInfo) Mode() fs.FileMode {
+  if d.e.isDir {
+    return fs.ModeDir | 0555
+  }
+  return 0444
+}
+
+// openDir implements fs.File and fs.ReadDirFile for a resolved
+// directory.
+type openDir struct {
+  name    string
+  entries []dirEntry
+  pos     int
+}
+
+func (d *openDir) Stat() (fs.FileInfo, error) {
+  return dirEntryInfo{dirEntry{name: d.name, isDir: true, mimeType: driveFolderMIME}}, nil
+}
+func (d *openDir) Read([]byte) (int, error) { return 0, &fs.PathError{Op: "read", Path: d.name, Err: fs.ErrInvalid} }
+func (d *openDir) Close() error             { return nil }
+
+func (d *openDir) ReadDir(n int) ([]fs.DirEntry, error) {
+  rem := d.entries[d.pos:]
+  if n <= 0 {
+    d.pos = len(d.entries)
+    out := make([]fs.DirEntry, len(rem))
+    for i, e := range rem {
+      out[i] = dirEntryInfo{e}
+    }
+    return out, nil
+  }
+  if len(rem) == 0 {
+    return nil, io.EOF
+  }
+  if n > len(rem) {
+    n = len(rem)
+  }
+  out := make([]fs.DirEntry, n)
+  for i, e := range rem[:n] {
+    out[i] = dirEntryInfo{e}
+  }
+  d.pos += n
+  return out, nil
+}
+
+// openFile implements fs.File for a resolved Drive file, lazily
+// downloading (or exporting, for Google-native types) its content on
+// the first Read.
+type openFile struct {
+  fsys *FS
+  name string
+  ent  dirEntry
+
+  body io.ReadCloser
+}
+
+func (o *openFile) Stat() (fs.FileInfo, error) { return dirEntryInfo{o.ent}, nil }
+
+func (o *openFile) Read(p []byte) (int, error) {
+  if o.body == nil {
+    body, err := o.open()
+    if err != nil {
+      return 0, &fs.PathError{Op: "read", Path: o.name, Err: err}
+    }
+    o.body = body
+  }
+  return o.body.Read(p)
+}
+
+func (o *openFile) Close() error {
+  if o.body == nil {
+    return nil
+  }
+  return o.body.Close()
+}
+
+func (o *openFile) open() (io.ReadCloser, error) {
+  svc := o.fsys.svc
+  if isNativeType(o.ent.mimeType) {
+    mime, ok := o.fsys.export[o.ent.mimeType]
+    if !ok {
+      return nil, fs.ErrNotExist
+    }
+    res, err := svc.Files.Export(o.ent.id, mime).Download()
+    if err != nil {
+      return nil, err
+    }
+    return res.Body, nil
+  }
+  res, err := svc.Files.Get(o.ent.id).Download()
+  if err != nil {
+    return nil, err
+  }
+  return res.Body, nil
+}
@@ -0,0 +1,174 @@
+package drivefs
+
+import (
+  "context"
+  "fmt"
+  "io/fs"
+  "path"
+  "sort"
+  "strings"
+  "time"
+
+  "google.golang.org/api/drive/v3"
+)
+
+const listFields = "nextPageToken, files(id, name, mimeType, trashed, modifiedTime, size, md5Checksum, shortcutDetails)"
+
+// children lists, resolves, and dedupes the entries of the directory
+// with the given file ID, following shortcuts and filtering
+// trashed/native files per the FS's configuration. Listing is
+// ordered by name (then createdTime to break ties between same-named
+// files), since the duplicate-name suffix assigned below must be
+// deterministic: Drive's API makes no ordering guarantee otherwise,
+// and which "report.pdf" becomes "report (1).pdf" would then depend
+// on whatever order a given List call happened to return.
+func (f *FS) children(ctx context.Context, dirID string) ([]dirEntry, error) {
+  if cached, ok := f.cache.get(dirID); ok {
+    return cached, nil
+  }
+
+  call := f.svc.Files.List().
+    Context(ctx).
+    PageSize(1000).
+    Fields(listFields).
+    OrderBy("name,createdTime").
+    Q(fmt.Sprintf("'%s' in parents and trashed = false", dirID))
+  if f.driveID != "" {
+    call = call.DriveId(f.driveID).Corpora("drive").
+      IncludeItemsFromAllDrives(true).SupportsAllDrives(true)
+  }
+
+  var files []*drive.File
+  err := call.Pages(ctx, func(r *drive.FileList) error {
+    files = append(files, r.Files...)
+    return nil
+  })
+  if err != nil {
+    return nil, err
+  }
+
+  entries := make([]dirEntry, 0, len(files))
+  seen := make(map[string]int, len(files))
+  for _, file := range files {
+    id, mimeType := file.Id, file.MimeType
+    if file.ShortcutDetails != nil {
+      id = file.ShortcutDetails.TargetId
+      mimeType = file.ShortcutDetails.TargetMimeType
+    }
+    if isNativeType(mimeType) && f.export == nil {
+      continue
+    }
+
+    name := file.Name
+    if n := seen[file.Name]; n > 0 {
+      name = dedupeName(file.Name, n)
+    }
+    seen[file.Name]++
+
+    modTime, _ := time.Parse(time.RFC3339, file.ModifiedTime)
+    entries = append(entries, dirEntry{
+      name:     name,
+      id:       id,
+      isDir:    mimeType == driveFolderMIME,
+      mimeType: mimeType,
+      modTime:  modTime,
+      md5:      file.Md5Checksum,
+      size:     file.Size,
+    })
+  }
+  sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+
+  f.cache.put(dirID, entries)
+  return entries, nil
+}
+
+// dedupeName returns the name Drive's nth duplicate (1-indexed) of
+// name should be addressed by within the FS, e.g. the second
+// "report.pdf" in a folder becomes "report (1).pdf".
+func dedupeName(name string, n int) string {
+  ext := ""
+  base := name
+  if i := strings.LastIndex(name, "."); i > 0 {
+    base, ext = name[:i], name[i:]
+  }
+  return fmt.Sprintf("%s (%d)%s", base, n, ext)
+}
+
+const driveFolderMIME = "application/vnd.google-apps.folder"
+
+func isNativeType(mimeType string) bool {
+  return strings.HasPrefix(mimeType, "application/vnd.google-apps.") && mimeType != driveFolderMIME
+}
+
+// cleanDrivePath strips a leading "/" so absolute-looking Drive
+// paths (e.g. "/Projects/Report.pdf")
+// resolve the same as the equivalent relative path; fs.ValidPath
+// otherwise rejects any leading slash outright. The empty result
+// becomes ".", meaning the root.
+func cleanDrivePath(name string) string {
+  name = strings.TrimPrefix(name, "/")
+  if name == "" {
+    return "."
+  }
+  return name
+}
+
+// resolve walks name, a slash-separated path rooted at the FS root,
+// one segment at a time and returns the dirEntry it resolves to. The
+// empty path and "." resolve to the root directory itself.
+func (f *FS) resolve(ctx context.Context, name string) (dirEntry, error) {
+  name = cleanDrivePath(name)
+  if !fs.ValidPath(name) {
+    return dirEntry{}, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+  }
+  if name == "." {
+    return dirEntry{name: ".", id: f.rootID(), isDir: true, mimeType: driveFolderMIME}, nil
+  }
+
+  cur := dirEntry{id: f.rootID(), isDir: true}
+  for _, seg := range strings.Split(name, "/") {
+    if !cur.isDir {
+      return dirEntry{}, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+    }
+    kids, err := f.children(ctx, cur.id)
+    if err != nil {
+      return dirEntry{}, &fs.PathError{Op: "open", Path: name, Err: err}
+    }
+    found := false
+    for _, k := range kids {
+      if k.name == seg {
+        cur, found = k, true
+        break
+      }
+    }
+    if !found {
+      return dirEntry{}, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+    }
+  }
+  return cur, nil
+}
+
+// ResolveParent splits name into its containing directory and final
+// path segment, resolving the directory to its Drive file ID without
+// requiring the final segment itself to exist. It is used by
+// callers that are about to create the thing at name (mkdir, put,
+// mv's destination).
+func (f *FS) ResolveParent(name string) (parentID, base string, err error) {
+  name = cleanDrivePath(name)
+  if !fs.ValidPath(name) || name == "." {
+    return "", "", &fs.PathError{Op: "resolve", Path: name, Err: fs.ErrInvalid}
+  }
+  dir, base := path.Split(name)
+  dir = strings.TrimSuffix(dir, "/")
+  if dir == "" {
+    return f.rootID(), base, nil
+  }
+  ent, err := f.resolve(context.Background(), dir)
+  if err != nil {
+    return "", "", err
+  }
+  if !ent.isDir {
+    return "", "", &fs.PathError{Op: "resolve", Path: name, Err: fs.ErrInvalid}
+  }
+  return ent.id, base, nil
+}
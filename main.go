@@ -0,0 +1,18 @@
+// Command drive is a command-line client for Google Drive: ls, get,
+// put, rm, mv, mkdir, sync, export, and about, built on the drivefs,
+// transfer, tokenstore, and auth packages.
+package main
+
+import (
+  "fmt"
+  "os"
+
+  "private/cli"
+)
+
+func main() {
+  if err := cli.NewRootCmd().Execute(); err != nil {
+    fmt.Fprintln(os.Stderr, err)
+    os.Exit(1)
+  }
+}
@@ -0,0 +1,15 @@
+package tokenstore
+
+import "errors"
+
+// errNotFound is returned by a TokenStore's Load when no token has
+// been saved yet. Callers treat any Load error as "go get a fresh
+// token", so the exact error is rarely inspected, but IsNotFound
+// lets callers that care check for it.
+var errNotFound = errors.New("tokenstore: no token stored")
+
+// IsNotFound reports whether err indicates that no token has been
+// saved yet, as opposed to some other failure reading the store.
+func IsNotFound(err error) bool {
+  return errors.Is(err, errNotFound)
+}
@@ -0,0 +1,70 @@
+package tokenstore
+
+import (
+  "context"
+  "encoding/json"
+  "fmt"
+  "os"
+  "path/filepath"
+
+  "golang.org/x/oauth2"
+)
+
+// File is a TokenStore backed by a single JSON file. It is the same
+// on-disk format the original drive-go-quickstart used
+// (~/.credentials/drive-go-quickstart.json), but Save now writes
+// atomically and enforces 0600 permissions even if the file already
+// existed with looser ones.
+type File struct {
+  Path string
+}
+
+func (s File) Load(ctx context.Context) (*oauth2.Token, error) {
+  f, err := os.Open(s.Path)
+  if err != nil {
+    if os.IsNotExist(err) {
+      return nil, errNotFound
+    }
+    return nil, err
+  }
+  defer f.Close()
+
+  tok := &oauth2.Token{}
+  if err := json.NewDecoder(f).Decode(tok); err != nil {
+    return nil, fmt.Errorf("tokenstore: decoding %s: %w", s.Path, err)
+  }
+  return tok, nil
+}
+
+// Save writes tok to a temp file in the same directory as Path and
+// renames it into place, so a crash mid-write can never leave a
+// truncated or empty credential file behind.
+func (s File) Save(ctx context.Context, tok *oauth2.Token) error {
+  dir := filepath.Dir(s.Path)
+  if err := os.MkdirAll(dir, 0700); err != nil {
+    return fmt.Errorf("tokenstore: creating %s: %w", dir, err)
+  }
+
+  tmp, err := os.CreateTemp(dir, filepath.Base(s.Path)+".tmp-*")
+  if err != nil {
+    return fmt.Errorf("tokenstore: creating temp file: %w", err)
+  }
+  tmpPath := tmp.Name()
+  defer os.Remove(tmpPath) // no-op once the rename below succeeds
+
+  if err := tmp.Chmod(0600); err != nil {
+    tmp.Close()
+    return fmt.Errorf("tokenstore: setting permissions: %w", err)
+  }
+  if err := json.NewEncoder(tmp).Encode(tok); err != nil {
+    tmp.Close()
+    return fmt.Errorf("tokenstore: encoding token: %w", err)
+  }
+  if err := tmp.Close(); err != nil {
+    return fmt.Errorf("tokenstore: closing temp file: %w", err)
+  }
+  if err := os.Rename(tmpPath, s.Path); err != nil {
+    return fmt.Errorf("tokenstore: renaming into place: %w", err)
+  }
+  return nil
+}
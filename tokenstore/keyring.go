@@ -0,0 +1,64 @@
+package tokenstore
+
+import (
+  "context"
+  "encoding/json"
+  "fmt"
+
+  "github.com/zalando/go-keyring"
+  "golang.org/x/oauth2"
+)
+
+// Keyring is a TokenStore backed by the OS credential store (macOS
+// Keychain, Secret Service on Linux, Windows Credential Manager).
+// Service and User identify the entry; both default to sensible
+// values when empty.
+type Keyring struct {
+  Service string
+  User    string
+}
+
+const (
+  defaultKeyringService = "drive-go-quickstart"
+  defaultKeyringUser    = "default"
+)
+
+func (s Keyring) service() string {
+  if s.Service != "" {
+    return s.Service
+  }
+  return defaultKeyringService
+}
+
+func (s Keyring) user() string {
+  if s.User != "" {
+    return s.User
+  }
+  return defaultKeyringUser
+}
+
+func (s Keyring) Load(ctx context.Context) (*oauth2.Token, error) {
+  secret, err := keyring.Get(s.service(), s.user())
+  if err != nil {
+    if err == keyring.ErrNotFound {
+      return nil, errNotFound
+    }
+    return nil, fmt.Errorf("tokenstore: reading keyring entry: %w", err)
+  }
+  tok := &oauth2.Token{}
+  if err := json.Unmarshal([]byte(secret), tok); err != nil {
+    return nil, fmt.Errorf("tokenstore: decoding keyring entry: %w", err)
+  }
+  return tok, nil
+}
+
+func (s Keyring) Save(ctx context.Context, tok *oauth2.Token) error {
+  b, err := json.Marshal(tok)
+  if err != nil {
+    return fmt.Errorf("tokenstore: encoding token: %w", err)
+  }
+  if err := keyring.Set(s.service(), s.user(), string(b)); err != nil {
+    return fmt.Errorf("tokenstore: writing keyring entry: %w", err)
+  }
+  return nil
+}
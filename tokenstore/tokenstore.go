@@ -0,0 +1,47 @@
+// Package tokenstore abstracts where an OAuth2 token is persisted
+// between runs, so the same client code works whether the token
+// lives in a file, the OS keyring, or nowhere at all.
+package tokenstore
+
+import (
+  "context"
+  "sync"
+
+  "golang.org/x/oauth2"
+)
+
+// TokenStore loads and saves the token used to authenticate Drive
+// API calls. Implementations are expected to be safe to share across
+// goroutines.
+type TokenStore interface {
+  // Load returns the previously saved token, or an error for which
+  // IsNotFound reports true if none is stored yet.
+  Load(ctx context.Context) (*oauth2.Token, error)
+  // Save persists tok, overwriting whatever was previously stored.
+  Save(ctx context.Context, tok *oauth2.Token) error
+}
+
+// Memory is a TokenStore that keeps the token only in process
+// memory. It is mainly useful for tests and for short-lived
+// credential modes (service account, ADC) that don't need a token
+// cache at all.
+type Memory struct {
+  mu  sync.Mutex
+  tok *oauth2.Token
+}
+
+func (m *Memory) Load(ctx context.Context) (*oauth2.Token, error) {
+  m.mu.Lock()
+  defer m.mu.Unlock()
+  if m.tok == nil {
+    return nil, errNotFound
+  }
+  return m.tok, nil
+}
+
+func (m *Memory) Save(ctx context.Context, tok *oauth2.Token) error {
+  m.mu.Lock()
+  defer m.mu.Unlock()
+  m.tok = tok
+  return nil
+}
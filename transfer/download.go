@@ -0,0 +1,60 @@
+package transfer
+
+import (
+  "context"
+  "fmt"
+  "io"
+
+  "google.golang.org/api/drive/v3"
+)
+
+// Download streams the content of fileID into w, honoring a byte
+// range when rangeStart/rangeEnd are non-negative (Drive's Get(...)
+// .Download() always fetches the whole file otherwise). It copies
+// until EOF via io.Copy, so content isn't truncated at whatever a
+// single Read happens to return.
+//
+// rangeEnd is inclusive; pass -1 to mean "to the end of the file".
+func Download(ctx context.Context, svc *drive.Service, fileID string, w io.Writer, rangeStart, rangeEnd int64, progress ProgressFunc) (int64, error) {
+  call := svc.Files.Get(fileID).Context(ctx)
+  if rangeStart >= 0 {
+    rng := fmt.Sprintf("bytes=%d-", rangeStart)
+    if rangeEnd >= 0 {
+      rng = fmt.Sprintf("bytes=%d-%d", rangeStart, rangeEnd)
+    }
+    call.Header().Set("Range", rng)
+  }
+
+  res, err := call.Download()
+  if err != nil {
+    return 0, fmt.Errorf("transfer: downloading file %s: %w", fileID, err)
+  }
+  defer res.Body.Close()
+
+  var total int64
+  if res.ContentLength > 0 {
+    total = res.ContentLength
+  }
+
+  if progress == nil {
+    n, err := io.Copy(w, res.Body)
+    return n, err
+  }
+  return io.Copy(w, &progressReader{r: res.Body, total: total, onRead: progress})
+}
+
+// progressReader wraps an io.Reader and reports cumulative bytes
+// read after each Read call.
+type progressReader struct {
+  r      io.Reader
+  total  int64
+  read   int64
+  onRead ProgressFunc
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+  n, err := p.r.Read(b)
+  p.read += int64(n)
+  p.onRead(p.read, p.total)
+  return n, err
+}
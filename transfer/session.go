@@ -0,0 +1,55 @@
+package transfer
+
+import (
+  "io/ioutil"
+  "net/url"
+  "os"
+  "path/filepath"
+)
+
+// SessionStore persists the resumable session URI for an in-progress
+// upload, keyed by an opaque string the caller chooses (typically
+// derived from the destination path and the content being uploaded). It lets Uploader.Upload resume
+// a chunked upload across process restarts instead of starting over.
+type SessionStore interface {
+  // Load returns the persisted session URI for key, if any.
+  Load(key string) (uri string, ok bool)
+  // Save persists uri as the session for key.
+  Save(key, uri string) error
+  // Clear removes any persisted session for key.
+  Clear(key string) error
+}
+
+// FileSessionStore persists sessions as one file per key under Dir.
+// It is the on-disk analog of the token cache file used for OAuth
+// credentials.
+type FileSessionStore struct {
+  Dir string
+}
+
+func (s FileSessionStore) path(key string) string {
+  return filepath.Join(s.Dir, url.QueryEscape(key)+".session")
+}
+
+func (s FileSessionStore) Load(key string) (string, bool) {
+  b, err := ioutil.ReadFile(s.path(key))
+  if err != nil {
+    return "", false
+  }
+  return string(b), true
+}
+
+func (s FileSessionStore) Save(key, uri string) error {
+  if err := os.MkdirAll(s.Dir, 0700); err != nil {
+    return err
+  }
+  return ioutil.WriteFile(s.path(key), []byte(uri), 0600)
+}
+
+func (s FileSessionStore) Clear(key string) error {
+  err := os.Remove(s.path(key))
+  if os.IsNotExist(err) {
+    return nil
+  }
+  return err
+}
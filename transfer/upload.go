@@ -0,0 +1,355 @@
+// Package transfer implements chunked, resumable upload and
+// range-aware streaming download for Drive file content, as an
+// alternative to buffering a whole file through Files.Update's
+// in-memory Media(io.Reader).
+package transfer
+
+import (
+  "bytes"
+  "context"
+  "encoding/json"
+  "fmt"
+  "io"
+  "net/http"
+  "strconv"
+  "strings"
+  "time"
+
+  "google.golang.org/api/drive/v3"
+)
+
+// DefaultChunkSize is the chunk size used when an Uploader is not
+// given one explicitly. It matches the default used by most
+// resumable Drive uploaders (e.g. transfer.sh's gdrive storage
+// backend) and is a multiple of the 256 KiB Drive requires for all
+// but the final chunk.
+const DefaultChunkSize = 8 * 1024 * 1024
+
+const uploadBaseURL = "https://www.googleapis.com/upload/drive/v3/files"
+
+// ProgressFunc is called after each chunk is successfully uploaded
+// or downloaded, with the number of bytes transferred so far and the
+// total size (0 if unknown).
+type ProgressFunc func(transferred, total int64)
+
+// Uploader performs chunked, resumable uploads of file content to
+// Drive. Unlike Files.Update(...).Media(r).Do(), it never buffers
+// the whole file, reports progress, retries individual chunks, and
+// can resume an interrupted upload across process restarts via a
+// SessionStore.
+type Uploader struct {
+  // Client is the authenticated HTTP client used to talk to the
+  // resumable upload endpoint.
+  Client *http.Client
+
+  // ChunkSize is the number of bytes uploaded per request. It must
+  // be a multiple of 256 KiB, except for the final chunk. Defaults
+  // to DefaultChunkSize.
+  ChunkSize int64
+
+  // Sessions persists the resumable session URI between calls to
+  // Upload, keyed by Upload's key argument. If nil, interrupted uploads cannot be
+  // resumed.
+  Sessions SessionStore
+
+  // Progress, if set, is called after each chunk.
+  Progress ProgressFunc
+}
+
+// Upload uploads r (of the given size) as the content of fileID,
+// creating a new file when fileID is empty. When creating a file,
+// parentID (if non-empty) sets the new file's parent folder. key
+// identifies the upload session for resumption; callers typically
+// derive it from the destination path and the content being uploaded.
+func (u *Uploader) Upload(ctx context.Context, fileID, parentID, name, mimeType string, r io.ReaderAt, size int64, key string) (*drive.File, error) {
+  chunkSize := u.ChunkSize
+  if chunkSize <= 0 {
+    chunkSize = DefaultChunkSize
+  }
+
+  sessionURI, offset, completedBody, err := u.resumeOrInit(ctx, fileID, parentID, name, mimeType, size, key)
+  if err != nil {
+    return nil, err
+  }
+  if completedBody != nil {
+    // The session we resumed had already gone to completion server-side
+    // (the process was killed after Drive received the last chunk but
+    // before the client saw that response); there's nothing left to PUT.
+    return u.completeUpload(completedBody, key)
+  }
+
+  if size == 0 {
+    // The for loop below never runs a PUT when size == 0 (offset < size
+    // is false from the start), so an empty file needs its own single
+    // request: the resumable upload protocol signals "this is the whole,
+    // zero-byte file" with an empty body and a rangeless Content-Range.
+    return u.finishUpload(ctx, sessionURI, key)
+  }
+
+  for offset < size {
+    end := offset + chunkSize
+    if end > size {
+      end = size
+    }
+    chunk := io.NewSectionReader(r, offset, end-offset)
+
+    status, body, err := u.putChunkWithRetry(ctx, sessionURI, chunk, offset, end-1, size)
+    if err != nil {
+      return nil, fmt.Errorf("transfer: uploading chunk [%d,%d): %w", offset, end, err)
+    }
+    offset = end
+    if u.Progress != nil {
+      u.Progress(offset, size)
+    }
+
+    if status == http.StatusOK || status == http.StatusCreated {
+      return u.completeUpload(body, key)
+    }
+    // status == 308 Resume Incomplete: keep going.
+  }
+  return nil, fmt.Errorf("transfer: upload loop exited without a completion response")
+}
+
+// finishUpload completes a zero-length upload: the resumable
+// protocol has no bytes to PUT, so it's finished by sending an empty
+// body with a Content-Range that states the total size (0) without a
+// byte range, the same shape queryOffset uses to ask "how much do you
+// have", which here is itself the completing request.
+func (u *Uploader) finishUpload(ctx context.Context, sessionURI, key string) (*drive.File, error) {
+  status, body, err := u.putChunkWithRetry(ctx, sessionURI, http.NoBody, -1, -1, 0)
+  if err != nil {
+    return nil, fmt.Errorf("transfer: uploading empty file: %w", err)
+  }
+  if status != http.StatusOK && status != http.StatusCreated {
+    return nil, fmt.Errorf("transfer: uploading empty file: unexpected status %d", status)
+  }
+  return u.completeUpload(body, key)
+}
+
+// completeUpload clears the persisted session (the upload is done,
+// so there's nothing left to resume) and decodes the final response
+// body into the created/updated drive.File.
+func (u *Uploader) completeUpload(body []byte, key string) (*drive.File, error) {
+  if u.Sessions != nil {
+    u.Sessions.Clear(key)
+  }
+  var f drive.File
+  if err := json.Unmarshal(body, &f); err != nil {
+    return nil, fmt.Errorf("transfer: decoding upload response: %w", err)
+  }
+  return &f, nil
+}
+
+// resumeOrInit either recovers the server's current byte offset for
+// a previously-persisted session (or, if that session already went to
+// completion server-side, its finished drive.File body), or starts a
+// new resumable session and persists its URI.
+func (u *Uploader) resumeOrInit(ctx context.Context, fileID, parentID, name, mimeType string, size int64, key string) (sessionURI string, offset int64, completedBody []byte, err error) {
+  if u.Sessions != nil {
+    if uri, ok := u.Sessions.Load(key); ok {
+      off, body, err := u.queryOffset(ctx, uri, size)
+      if err == nil {
+        return uri, off, body, nil
+      }
+      // The persisted session is no longer valid server-side; fall
+      // through and start a fresh one.
+      u.Sessions.Clear(key)
+    }
+  }
+
+  uri, err := u.initSession(ctx, fileID, parentID, name, mimeType, size)
+  if err != nil {
+    return "", 0, nil, err
+  }
+  if u.Sessions != nil {
+    u.Sessions.Save(key, uri)
+  }
+  return uri, 0, nil, nil
+}
+
+// initSession starts a new resumable upload session and returns the
+// session URI from the response's Location header.
+func (u *Uploader) initSession(ctx context.Context, fileID, parentID, name, mimeType string, size int64) (string, error) {
+  method, url := http.MethodPost, uploadBaseURL+"?uploadType=resumable&fields=id,name,mimeType,size,md5Checksum,modifiedTime"
+  if fileID != "" {
+    method, url = http.MethodPatch, fmt.Sprintf("%s/%s?uploadType=resumable&fields=id,name,mimeType,size,md5Checksum,modifiedTime", uploadBaseURL, fileID)
+  }
+
+  meta := map[string]interface{}{}
+  if name != "" {
+    meta["name"] = name
+  }
+  if parentID != "" && fileID == "" {
+    meta["parents"] = []string{parentID}
+  }
+  body, err := json.Marshal(meta)
+  if err != nil {
+    return "", err
+  }
+
+  req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+  if err != nil {
+    return "", err
+  }
+  req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+  if mimeType != "" {
+    req.Header.Set("X-Upload-Content-Type", mimeType)
+  }
+  req.Header.Set("X-Upload-Content-Length", strconv.FormatInt(size, 10))
+
+  resp, err := u.Client.Do(req)
+  if err != nil {
+    return "", err
+  }
+  defer resp.Body.Close()
+  if resp.StatusCode != http.StatusOK {
+    return "", fmt.Errorf("transfer: initiating resumable session: unexpected status %s", resp.Status)
+  }
+  loc := resp.Header.Get("Location")
+  if loc == "" {
+    return "", fmt.Errorf("transfer: resumable session response missing Location header")
+  }
+  return loc, nil
+}
+
+// queryOffset asks the server how many bytes of a previously started
+// session it has already received, per the resumable upload
+// protocol's "query current status" request. If the session had
+// already gone to completion server-side, it instead returns the
+// finished drive.File response body in completedBody, since there is
+// no more meaningful "offset" to report in that case.
+func (u *Uploader) queryOffset(ctx context.Context, sessionURI string, size int64) (offset int64, completedBody []byte, err error) {
+  req, err := http.NewRequestWithContext(ctx, http.MethodPut, sessionURI, nil)
+  if err != nil {
+    return 0, nil, err
+  }
+  req.Header.Set("Content-Range", fmt.Sprintf("bytes */%d", size))
+  resp, err := u.Client.Do(req)
+  if err != nil {
+    return 0, nil, err
+  }
+  defer resp.Body.Close()
+
+  switch resp.StatusCode {
+  case http.StatusOK, http.StatusCreated:
+    body, err := io.ReadAll(resp.Body)
+    if err != nil {
+      return 0, nil, err
+    }
+    return size, body, nil
+  case 308:
+    rng := resp.Header.Get("Range")
+    if rng == "" {
+      return 0, nil, nil
+    }
+    var lo, hi int64
+    if _, err := fmt.Sscanf(rng, "bytes=%d-%d", &lo, &hi); err != nil {
+      return 0, nil, fmt.Errorf("transfer: parsing Range header %q: %w", rng, err)
+    }
+    return hi + 1, nil, nil
+  default:
+    return 0, nil, fmt.Errorf("transfer: querying session status: unexpected status %s", resp.Status)
+  }
+}
+
+// putChunkWithRetry uploads one chunk, retrying on 429 and 5xx
+// responses with exponential backoff, honoring Retry-After when
+// present.
+func (u *Uploader) putChunkWithRetry(ctx context.Context, sessionURI string, chunk io.Reader, start, end, total int64) (status int, body []byte, err error) {
+  const maxAttempts = 6
+  backoff := time.Second
+
+  for attempt := 0; attempt < maxAttempts; attempt++ {
+    var header http.Header
+    status, body, header, err = u.putChunk(ctx, sessionURI, chunk, start, end, total)
+    if err != nil {
+      return 0, nil, err
+    }
+    if status != http.StatusTooManyRequests && status < 500 {
+      return status, body, nil
+    }
+
+    wait := backoff
+    if ra := retryAfter(header, body); ra > 0 {
+      wait = ra
+    }
+    select {
+    case <-time.After(wait):
+    case <-ctx.Done():
+      return 0, nil, ctx.Err()
+    }
+    backoff *= 2
+
+    // Rewind the chunk for the retry: start/end addressing is
+    // stable, so a SectionReader chunk is sent again from its start.
+    if sr, ok := chunk.(*io.SectionReader); ok {
+      sr.Seek(0, io.SeekStart)
+    }
+  }
+  return status, body, fmt.Errorf("transfer: chunk upload failed after %d attempts, last status %d", maxAttempts, status)
+}
+
+// putChunk issues one PUT against the resumable session. start < 0
+// means "this PUT has no bytes" (the empty-file completion request),
+// which addresses the Content-Range by total size alone rather than
+// a byte range, per the resumable upload protocol.
+func (u *Uploader) putChunk(ctx context.Context, sessionURI string, chunk io.Reader, start, end, total int64) (int, []byte, http.Header, error) {
+  req, err := http.NewRequestWithContext(ctx, http.MethodPut, sessionURI, chunk)
+  if err != nil {
+    return 0, nil, nil, err
+  }
+  if start < 0 {
+    req.ContentLength = 0
+    req.Header.Set("Content-Range", fmt.Sprintf("bytes */%d", total))
+  } else {
+    req.ContentLength = end - start + 1
+    req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, total))
+  }
+
+  resp, err := u.Client.Do(req)
+  if err != nil {
+    return 0, nil, nil, err
+  }
+  defer resp.Body.Close()
+  body, err := io.ReadAll(resp.Body)
+  if err != nil {
+    return 0, nil, nil, err
+  }
+  return resp.StatusCode, body, resp.Header, nil
+}
+
+// retryAfter determines how long to wait before retrying a throttled
+// or failed chunk upload. It prefers the standard Retry-After header
+// (seconds or an HTTP-date), and falls back to grepping the JSON
+// error body for a rateLimit or backendError reason when the header
+// is absent or unparseable,
+// since not every edge proxy in front of the upload endpoint sets it.
+func retryAfter(header http.Header, body []byte) time.Duration {
+  if v := header.Get("Retry-After"); v != "" {
+    if secs, err := strconv.Atoi(v); err == nil {
+      return time.Duration(secs) * time.Second
+    }
+    if t, err := http.ParseTime(v); err == nil {
+      if d := time.Until(t); d > 0 {
+        return d
+      }
+    }
+  }
+
+  var e struct {
+    Error struct {
+      Errors []struct {
+        Reason string `json:"reason"`
+      } `json:"errors"`
+    } `json:"error"`
+  }
+  if json.Unmarshal(body, &e) != nil {
+    return 0
+  }
+  for _, er := range e.Error.Errors {
+    if strings.Contains(er.Reason, "rateLimit") || strings.Contains(er.Reason, "backendError") {
+      return 2 * time.Second
+    }
+  }
+  return 0
+}